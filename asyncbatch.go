@@ -0,0 +1,194 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// overflowKind identifies which backpressure policy an OverflowPolicy
+// represents.
+type overflowKind int
+
+const (
+	overflowUnset overflowKind = iota // zero value: caller didn't set AsyncOpts.Overflow
+	overflowDropOldest
+	overflowDropNewest
+	overflowBlock
+	overflowSampleAtLevel
+)
+
+// OverflowPolicy decides what an AsyncBatchWriter does with a record that
+// arrives while its internal queue is full.
+type OverflowPolicy struct {
+	kind      overflowKind
+	threshold Level
+}
+
+var (
+	// DropOldest evicts the queue's oldest record to make room for the
+	// incoming one.
+	DropOldest = OverflowPolicy{kind: overflowDropOldest}
+	// DropNewest discards the incoming record, leaving the queue as is.
+	DropNewest = OverflowPolicy{kind: overflowDropNewest}
+	// Block makes LogWrite block until the queue has room, the same
+	// backpressure behavior as a LogWriter's own LogBufferLength channel.
+	Block = OverflowPolicy{kind: overflowBlock}
+)
+
+// SampleAtLevel drops incoming records below lvl while the queue is full,
+// but blocks (as Block does) for lvl and above so that important messages
+// are never silently discarded.
+func SampleAtLevel(lvl Level) OverflowPolicy {
+	return OverflowPolicy{kind: overflowSampleAtLevel, threshold: lvl}
+}
+
+// AsyncOpts configures an AsyncBatchWriter.
+type AsyncOpts struct {
+	// BatchSize is the number of records coalesced into one flush.
+	BatchSize int
+	// FlushInterval is the maximum time a record waits before being
+	// flushed, even if BatchSize hasn't been reached.
+	FlushInterval time.Duration
+	// QueueSize bounds how many records may be buffered before Overflow
+	// is consulted. Defaults to 1024 if zero.
+	QueueSize int
+	// Overflow is the backpressure policy applied once the queue is
+	// full. Defaults to Block if unset.
+	Overflow OverflowPolicy
+}
+
+// AsyncBatchWriter wraps another LogWriter, coalescing records into
+// size- or time-bounded batches and applying a configurable overflow
+// policy once its bounded queue is full, instead of always blocking as a
+// LogWriter's own fixed-size channel does.
+type AsyncBatchWriter struct {
+	inner LogWriter
+	opts  AsyncOpts
+	queue chan *LogRecord
+	done  chan struct{}
+
+	dropped uint64
+	flushed uint64
+}
+
+// NewAsyncBatchWriter wraps inner with batching and backpressure as
+// described by opts.
+func NewAsyncBatchWriter(inner LogWriter, opts AsyncOpts) *AsyncBatchWriter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 200 * time.Millisecond
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.Overflow.kind == overflowUnset {
+		opts.Overflow = Block
+	}
+
+	w := &AsyncBatchWriter{
+		inner: inner,
+		opts:  opts,
+		queue: make(chan *LogRecord, opts.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *AsyncBatchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*LogRecord, 0, w.opts.BatchSize)
+	flush := func() {
+		for _, rec := range batch {
+			w.inner.LogWrite(rec)
+		}
+		atomic.AddUint64(&w.flushed, uint64(len(batch)))
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case rec, ok := <-w.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, rec)
+			if len(batch) >= w.opts.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				flush()
+			}
+		}
+	}
+}
+
+// LogWrite enqueues rec, applying the configured OverflowPolicy if the
+// internal queue is full.
+func (w *AsyncBatchWriter) LogWrite(rec *LogRecord) {
+	select {
+	case w.queue <- rec:
+		return
+	default:
+	}
+
+	switch w.opts.Overflow.kind {
+	case overflowBlock:
+		w.queue <- rec
+	case overflowDropNewest:
+		atomic.AddUint64(&w.dropped, 1)
+	case overflowDropOldest:
+		select {
+		case <-w.queue:
+			atomic.AddUint64(&w.dropped, 1)
+		default:
+		}
+		select {
+		case w.queue <- rec:
+		default:
+			atomic.AddUint64(&w.dropped, 1)
+		}
+	case overflowSampleAtLevel:
+		if rec.Level < w.opts.Overflow.threshold {
+			atomic.AddUint64(&w.dropped, 1)
+			return
+		}
+		w.queue <- rec
+	}
+}
+
+// Close stops accepting new records, flushes whatever is queued, and
+// closes the wrapped LogWriter.
+func (w *AsyncBatchWriter) Close() {
+	close(w.queue)
+	<-w.done
+	w.inner.Close()
+}
+
+// Dropped returns the number of records discarded by the overflow policy
+// so far.
+func (w *AsyncBatchWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Flushed returns the number of records written to the wrapped LogWriter
+// so far.
+func (w *AsyncBatchWriter) Flushed() uint64 {
+	return atomic.LoadUint64(&w.flushed)
+}
+
+// QueueDepth returns the number of records currently waiting in the queue.
+// Records already taken into the batch being assembled are not counted.
+func (w *AsyncBatchWriter) QueueDepth() int {
+	return len(w.queue)
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"testing"
+	"time"
+)
+
+// blockingLogWriter blocks every LogWrite on release until it is closed, so
+// an AsyncBatchWriter's run() goroutine can be pinned inside flush() to
+// deterministically fill its queue.
+type blockingLogWriter struct {
+	release chan struct{}
+}
+
+func newBlockingLogWriter() *blockingLogWriter {
+	return &blockingLogWriter{release: make(chan struct{})}
+}
+
+func (w *blockingLogWriter) LogWrite(rec *LogRecord) {
+	<-w.release
+}
+
+func (w *blockingLogWriter) Close() {}
+
+// primeFullQueue writes one record (drained into run()'s blocked flush) and
+// a second (left sitting in the now-empty, single-slot queue), leaving the
+// queue full and run() stuck delivering the first record to inner.
+func primeFullQueue(t *testing.T, w *AsyncBatchWriter) {
+	t.Helper()
+
+	w.LogWrite(&LogRecord{Level: CRITICAL, Message: "prime-1"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for w.QueueDepth() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for AsyncBatchWriter to drain the priming record")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	w.LogWrite(&LogRecord{Level: CRITICAL, Message: "prime-2"})
+}
+
+func TestAsyncBatchWriterDropNewestCountsOverflow(t *testing.T) {
+	inner := newBlockingLogWriter()
+	w := NewAsyncBatchWriter(inner, AsyncOpts{QueueSize: 1, BatchSize: 1, Overflow: DropNewest})
+	primeFullQueue(t, w)
+
+	w.LogWrite(&LogRecord{Level: INFO, Message: "overflow"})
+
+	if got, want := w.Dropped(), uint64(1); got != want {
+		t.Errorf("Dropped() = %d, want %d", got, want)
+	}
+
+	close(inner.release)
+	w.Close()
+}
+
+func TestAsyncBatchWriterDropOldestCountsOverflow(t *testing.T) {
+	inner := newBlockingLogWriter()
+	w := NewAsyncBatchWriter(inner, AsyncOpts{QueueSize: 1, BatchSize: 1, Overflow: DropOldest})
+	primeFullQueue(t, w)
+
+	w.LogWrite(&LogRecord{Level: INFO, Message: "overflow"})
+
+	if got, want := w.Dropped(), uint64(1); got != want {
+		t.Errorf("Dropped() = %d, want %d", got, want)
+	}
+	if got, want := w.QueueDepth(), 1; got != want {
+		t.Errorf("QueueDepth() = %d, want %d (incoming record took the evicted slot)", got, want)
+	}
+
+	close(inner.release)
+	w.Close()
+}
+
+func TestAsyncBatchWriterSampleAtLevelDropsBelowThresholdOnly(t *testing.T) {
+	inner := newBlockingLogWriter()
+	w := NewAsyncBatchWriter(inner, AsyncOpts{QueueSize: 1, BatchSize: 1, Overflow: SampleAtLevel(WARNING)})
+	primeFullQueue(t, w)
+
+	w.LogWrite(&LogRecord{Level: DEBUG, Message: "below threshold"})
+	if got, want := w.Dropped(), uint64(1); got != want {
+		t.Errorf("Dropped() = %d, want %d after a below-threshold record arrived while full", got, want)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		// Above the threshold: must block for room rather than drop, so
+		// this only returns once close(inner.release) lets run() free a
+		// queue slot.
+		w.LogWrite(&LogRecord{Level: CRITICAL, Message: "at/above threshold"})
+		close(done)
+	}()
+
+	close(inner.release)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the at/above-threshold LogWrite to unblock")
+	}
+
+	if got, want := w.Dropped(), uint64(1); got != want {
+		t.Errorf("Dropped() = %d, want %d (at/above-threshold record must not be dropped)", got, want)
+	}
+
+	w.Close()
+}
+
+func TestAsyncBatchWriterFlushedCountsWrittenRecords(t *testing.T) {
+	inner := newBlockingLogWriter()
+	close(inner.release) // never blocks; every flush completes immediately
+
+	w := NewAsyncBatchWriter(inner, AsyncOpts{BatchSize: 2, FlushInterval: time.Minute})
+	w.LogWrite(&LogRecord{Level: INFO, Message: "a"})
+	w.LogWrite(&LogRecord{Level: INFO, Message: "b"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for w.Flushed() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the batch to flush")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	w.Close()
+
+	if got, want := w.Flushed(), uint64(2); got != want {
+		t.Errorf("Flushed() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xmlProperty represents a single <property> element inside a <filter>.
+type xmlProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// xmlAsync represents an <async batch="100" flush="200ms"
+// overflow="drop-oldest"/> element nested inside a <filter>.
+type xmlAsync struct {
+	Batch    int    `xml:"batch,attr"`
+	Flush    string `xml:"flush,attr"`
+	Overflow string `xml:"overflow,attr"`
+}
+
+// xmlFilter represents a <filter> element in an XML config file.
+type xmlFilter struct {
+	Enabled    string        `xml:"enabled,attr"`
+	Tag        string        `xml:"tag"`
+	Level      string        `xml:"level"`
+	Type       string        `xml:"type"`
+	Properties []xmlProperty `xml:"property"`
+	Async      *xmlAsync     `xml:"async"`
+}
+
+// xmlLoggerConfig is the root element of an XML config file.
+type xmlLoggerConfig struct {
+	Filters []xmlFilter `xml:"filter"`
+}
+
+// jsonAsync mirrors xmlAsync for JSON config files.
+type jsonAsync struct {
+	Batch    int    `json:"batch"`
+	Flush    string `json:"flush"`
+	Overflow string `json:"overflow"`
+}
+
+// jsonFilter mirrors xmlFilter for JSON config files.
+type jsonFilter struct {
+	Enabled    string            `json:"enabled"`
+	Tag        string            `json:"tag"`
+	Level      string            `json:"level"`
+	Type       string            `json:"type"`
+	Properties map[string]string `json:"properties"`
+	Async      *jsonAsync        `json:"async"`
+}
+
+// jsonLoggerConfig is the root element of a JSON config file.
+type jsonLoggerConfig struct {
+	Filters []jsonFilter `json:"filter"`
+}
+
+func levelFromString(s string) (Level, bool) {
+	switch strings.ToUpper(s) {
+	case "FINEST":
+		return FINEST, true
+	case "FINE":
+		return FINE, true
+	case "DEBUG":
+		return DEBUG, true
+	case "TRACE":
+		return TRACE, true
+	case "INFO":
+		return INFO, true
+	case "WARNING":
+		return WARNING, true
+	case "ERROR":
+		return ERROR, true
+	case "CRITICAL":
+		return CRITICAL, true
+	}
+	return 0, false
+}
+
+// LoadConfiguration reads XML configuration from filename and adds each
+// enabled filter it finds to the logger.
+func (log Logger) LoadConfiguration(filename string) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "LoadConfiguration: %s\n", err)
+		os.Exit(1)
+	}
+
+	var config xmlLoggerConfig
+	if err := xml.Unmarshal(contents, &config); err != nil {
+		fmt.Fprintf(os.Stderr, "LoadConfiguration: %s\n", err)
+		os.Exit(1)
+	}
+
+	for _, f := range config.Filters {
+		var async *asyncConfig
+		if f.Async != nil {
+			async = &asyncConfig{Batch: f.Async.Batch, Flush: f.Async.Flush, Overflow: f.Async.Overflow}
+		}
+		log.loadFilter(f.Enabled, f.Tag, f.Level, f.Type, xmlPropsToMap(f.Properties), async)
+	}
+}
+
+// LoadJsonConfiguration reads JSON configuration from filename and adds
+// each enabled filter it finds to the logger.
+func (log Logger) LoadJsonConfiguration(filename string) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "LoadJsonConfiguration: %s\n", err)
+		os.Exit(1)
+	}
+
+	var config jsonLoggerConfig
+	if err := json.Unmarshal(contents, &config); err != nil {
+		fmt.Fprintf(os.Stderr, "LoadJsonConfiguration: %s\n", err)
+		os.Exit(1)
+	}
+
+	for _, f := range config.Filters {
+		var async *asyncConfig
+		if f.Async != nil {
+			async = &asyncConfig{Batch: f.Async.Batch, Flush: f.Async.Flush, Overflow: f.Async.Overflow}
+		}
+		log.loadFilter(f.Enabled, f.Tag, f.Level, f.Type, f.Properties, async)
+	}
+}
+
+func xmlPropsToMap(props []xmlProperty) map[string]string {
+	out := make(map[string]string, len(props))
+	for _, p := range props {
+		out[p.Name] = strings.TrimSpace(p.Value)
+	}
+	return out
+}
+
+// asyncConfig is the parsed form of an <async> element nested inside a
+// <filter>, common to both the XML and JSON loaders.
+type asyncConfig struct {
+	Batch    int
+	Flush    string
+	Overflow string
+}
+
+// loadFilter builds a LogWriter for a single filter entry, optionally
+// wraps it in an AsyncBatchWriter per async, and adds it to the logger
+// under the given tag.
+func (log Logger) loadFilter(enabled, tag, level, kind string, props map[string]string, async *asyncConfig) {
+	if b, err := strconv.ParseBool(enabled); err == nil && !b {
+		return
+	}
+
+	lvl, ok := levelFromString(level)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "loadFilter(%q): unknown level %q\n", tag, level)
+		return
+	}
+
+	writer := newLogWriter(kind, props)
+	if writer == nil {
+		fmt.Fprintf(os.Stderr, "loadFilter(%q): unknown or misconfigured type %q\n", tag, kind)
+		return
+	}
+
+	if async != nil {
+		writer = NewAsyncBatchWriter(writer, async.toAsyncOpts())
+	}
+
+	log.AddFilter(tag, lvl, writer)
+}
+
+// toAsyncOpts converts the parsed <async> element into AsyncOpts,
+// defaulting FlushInterval to 200ms and Overflow to Block when unset or
+// unparsable.
+func (a *asyncConfig) toAsyncOpts() AsyncOpts {
+	opts := AsyncOpts{BatchSize: a.Batch, Overflow: Block}
+
+	if d, err := time.ParseDuration(a.Flush); err == nil {
+		opts.FlushInterval = d
+	}
+
+	switch {
+	case a.Overflow == "drop-oldest":
+		opts.Overflow = DropOldest
+	case a.Overflow == "drop-newest":
+		opts.Overflow = DropNewest
+	case a.Overflow == "block":
+		opts.Overflow = Block
+	case strings.HasPrefix(a.Overflow, "sample-at:"):
+		if lvl, ok := levelFromString(strings.TrimPrefix(a.Overflow, "sample-at:")); ok {
+			opts.Overflow = SampleAtLevel(lvl)
+		}
+	}
+
+	return opts
+}
+
+// newLogWriter builds the LogWriter for a <filter type="..."> entry. It is
+// extended by each LogWriter implementation that wants to be configurable.
+func newLogWriter(kind string, props map[string]string) LogWriter {
+	switch kind {
+	case "console":
+		return NewConsoleLogWriter()
+	case "file":
+		w := NewFileLogWriter(props["filename"], props["rotate"] == "true")
+		if w == nil {
+			return nil
+		}
+		if mb, err := strconv.Atoi(props["maxsizemb"]); err == nil && mb > 0 {
+			w.SetRotateSizeMB(mb)
+		}
+		if every, err := time.ParseDuration(props["rotateevery"]); err == nil && every > 0 {
+			w.SetRotateEvery(every)
+		}
+		if props["compress"] == "true" {
+			w.SetCompress(true)
+		}
+		if n, err := strconv.Atoi(props["maxbackups"]); err == nil && n > 0 {
+			w.SetMaxBackups(n)
+		}
+		if days, err := strconv.Atoi(props["maxagedays"]); err == nil && days > 0 {
+			w.SetMaxAgeDays(days)
+		}
+		return w
+	case "socket":
+		if w := NewSocketLogWriter(props["protocol"], props["endpoint"]); w != nil {
+			return w
+		}
+	case "json":
+		if w := NewJsonFormatLogWriter(props["filename"]); w != nil {
+			return w
+		}
+	case "syslog":
+		if w := NewSyslogLogWriter(props["network"], props["addr"], props["facility"], props["tag"]); w != nil {
+			return w
+		}
+	case "journald":
+		if w := NewJournaldLogWriter(props["tag"]); w != nil {
+			return w
+		}
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// rotatedTimeFormat is the timestamp layout used in rotated segment names,
+// e.g. app.log.2006-01-02T15-04-05.gz
+const rotatedTimeFormat = "2006-01-02T15-04-05"
+
+// This log writer sends output to a file
+type FileLogWriter struct {
+	rec chan *LogRecord
+	rot chan bool
+
+	// The opened file
+	filename string
+	file     *os.File
+
+	// The logging format
+	format string
+
+	// Rotate at linecount
+	maxlines          int
+	maxlines_curlines int
+
+	// Rotate at size
+	maxsize         int
+	maxsize_cursize int
+
+	// Rotate daily
+	daily          bool
+	daily_opendate int
+
+	// Keep old logfiles (.001, .002, etc)
+	rotate bool
+
+	// Rotate every fixed duration (hourly, weekly, ...), independent of
+	// the daily/maxlines/maxsize triggers above.
+	rotateEvery time.Duration
+	nextRotate  time.Time
+
+	// gzip each rotated segment in the background once it's closed.
+	compress bool
+
+	// Retention: keep at most maxBackups rotated segments, and/or
+	// discard any rotated segment older than maxAgeDays.
+	maxBackups int
+	maxAgeDays int
+
+	// rotateSeq disambiguates segments rotated within the same second
+	// (rotatedTimeFormat only has 1-second resolution), so a size-triggered
+	// burst never collides two rotations onto the same archived name.
+	rotateSeq uint64
+}
+
+// usesTimestampedRotation reports whether this writer should name rotated
+// segments with the app.log.<timestamp>[.gz] template rather than the
+// legacy numbered .NNN suffix. That is the case whenever compression,
+// retention or RotateEvery is configured.
+func (w *FileLogWriter) usesTimestampedRotation() bool {
+	return w.compress || w.maxBackups > 0 || w.maxAgeDays > 0 || w.rotateEvery > 0
+}
+
+// NewFileLogWriter creates a new LogWriter which writes to the given file and
+// has rotation enabled if rotate is true.
+func NewFileLogWriter(fname string, rotate bool) *FileLogWriter {
+	w := &FileLogWriter{
+		rec:      make(chan *LogRecord, LogBufferLength),
+		rot:      make(chan bool),
+		filename: fname,
+		format:   FORMAT_DEFAULT,
+		rotate:   rotate,
+	}
+
+	if err := w.intRotate(); err != nil {
+		fmt.Fprintf(os.Stderr, "FileLogWriter(%q): %s\n", w.filename, err)
+		return nil
+	}
+
+	go w.run()
+	return w
+}
+
+func (w *FileLogWriter) run() {
+	for {
+		select {
+		case <-w.rot:
+			if err := w.intRotate(); err != nil {
+				fmt.Fprintf(os.Stderr, "FileLogWriter(%q): %s\n", w.filename, err)
+				return
+			}
+		case rec, ok := <-w.rec:
+			if !ok {
+				w.file.Close()
+				return
+			}
+			now := time.Now()
+			if (w.maxlines > 0 && w.maxlines_curlines >= w.maxlines) ||
+				(w.maxsize > 0 && w.maxsize_cursize >= w.maxsize) ||
+				(w.daily && now.Day() != w.daily_opendate) ||
+				(w.rotateEvery > 0 && !w.nextRotate.IsZero() && now.After(w.nextRotate)) {
+				if err := w.intRotate(); err != nil {
+					fmt.Fprintf(os.Stderr, "FileLogWriter(%q): %s\n", w.filename, err)
+					return
+				}
+			}
+
+			locked := lockFile(w.file) == nil
+			// Another process mid-write just means our write interleaves
+			// with theirs rather than getting lost, so a failed
+			// (non-blocking) lock isn't worth stalling over.
+
+			n, err := fmt.Fprint(w.file, FormatLogRecord(w.format, rec))
+
+			if locked {
+				unlockFile(w.file)
+			}
+
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "FileLogWriter(%q): %s\n", w.filename, err)
+				return
+			}
+
+			w.maxlines_curlines++
+			w.maxsize_cursize += n
+		}
+	}
+}
+
+// Rotate intRotate closes the current file, renames it if rotate is enabled,
+// and reopens the file at w.filename for appending; it is never truncated.
+func (w *FileLogWriter) intRotate() error {
+	if w.file != nil {
+		fmt.Fprint(w.file, FormatLogRecord(FORMAT_SHORT, &LogRecord{Created: time.Now()}))
+		w.file.Close()
+	}
+
+	if w.rotate && w.file != nil {
+		if w.usesTimestampedRotation() {
+			if err := w.archiveTimestamped(); err != nil {
+				return err
+			}
+		} else if _, err := os.Lstat(w.filename); err == nil {
+			num := 1
+			for ; err == nil && num <= 999; num++ {
+				fname := w.filename + fmt.Sprintf(".%03d", num)
+				_, err = os.Lstat(fname)
+			}
+			if err == nil {
+				return fmt.Errorf("rotate: cannot find free log number to rename %s", w.filename)
+			}
+			os.Rename(w.filename, w.filename+fmt.Sprintf(".%03d", num-1))
+		}
+	}
+
+	fd, err := os.OpenFile(w.filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660)
+	if err != nil {
+		return err
+	}
+	w.file = fd
+
+	now := time.Now()
+	fmt.Fprint(w.file, FormatLogRecord(FORMAT_SHORT, &LogRecord{Created: now}))
+
+	w.maxlines_curlines = 0
+	w.maxsize_cursize = 0
+	w.daily_opendate = now.Day()
+	if w.rotateEvery > 0 {
+		w.nextRotate = now.Add(w.rotateEvery)
+	}
+
+	return nil
+}
+
+// archiveTimestamped renames the just-closed w.filename to
+// app.log.<timestamp>, compresses it in the background if w.compress is
+// set, and prunes old segments per w.maxBackups/w.maxAgeDays.
+func (w *FileLogWriter) archiveTimestamped() error {
+	if _, err := os.Lstat(w.filename); err != nil {
+		return nil // nothing to rotate yet
+	}
+
+	archived := fmt.Sprintf("%s.%s", w.filename, time.Now().Format(rotatedTimeFormat))
+	_, plainErr := os.Lstat(archived)
+	_, gzErr := os.Lstat(archived + ".gz")
+	if plainErr == nil || gzErr == nil {
+		seq := atomic.AddUint64(&w.rotateSeq, 1)
+		archived = fmt.Sprintf("%s.%03d", archived, seq%1000)
+	}
+	if err := os.Rename(w.filename, archived); err != nil {
+		return err
+	}
+
+	go w.finishArchiving(archived)
+	return nil
+}
+
+// finishArchiving compresses a freshly rotated segment (if requested) and
+// then enforces retention. It runs in its own goroutine so log writes
+// aren't blocked on gzip or filesystem housekeeping.
+func (w *FileLogWriter) finishArchiving(archived string) {
+	if w.compress {
+		compressed, err := gzipFile(archived)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "FileLogWriter(%q): compress %q: %s\n", w.filename, archived, err)
+		} else {
+			archived = compressed
+		}
+	}
+
+	if err := pruneRotatedSegments(w.filename, w.maxBackups, w.maxAgeDays); err != nil {
+		fmt.Fprintf(os.Stderr, "FileLogWriter(%q): prune: %s\n", w.filename, err)
+	}
+}
+
+// LogWrite logs a record to the file writer.
+func (w *FileLogWriter) LogWrite(rec *LogRecord) {
+	w.rec <- rec
+}
+
+// Close stops the goroutine and closes the file.
+func (w *FileLogWriter) Close() {
+	close(w.rec)
+}
+
+// SetFormat sets the log format that will be used for messages.
+func (w *FileLogWriter) SetFormat(format string) *FileLogWriter {
+	w.format = format
+	return w
+}
+
+// SetRotateLines sets the maximum number of lines to write before rotating.
+func (w *FileLogWriter) SetRotateLines(maxlines int) *FileLogWriter {
+	w.maxlines = maxlines
+	return w
+}
+
+// SetRotateSize sets the maximum size in bytes to write before rotating.
+func (w *FileLogWriter) SetRotateSize(maxsize int) *FileLogWriter {
+	w.maxsize = maxsize
+	return w
+}
+
+// SetRotateDaily enables or disables rotation at the start of each day.
+func (w *FileLogWriter) SetRotateDaily(daily bool) *FileLogWriter {
+	w.daily = daily
+	return w
+}
+
+// SetRotateSizeMB sets the maximum size in megabytes to write before
+// rotating. It's a convenience wrapper around SetRotateSize.
+func (w *FileLogWriter) SetRotateSizeMB(maxSizeMB int) *FileLogWriter {
+	return w.SetRotateSize(maxSizeMB * 1024 * 1024)
+}
+
+// SetRotateEvery enables rotation on a fixed cadence (hourly, weekly,
+// ...), independent of the size/line/daily triggers above.
+func (w *FileLogWriter) SetRotateEvery(every time.Duration) *FileLogWriter {
+	w.rotateEvery = every
+	return w
+}
+
+// SetCompress enables gzip compression of each rotated segment, done in
+// a background goroutine so it never blocks log writes.
+func (w *FileLogWriter) SetCompress(compress bool) *FileLogWriter {
+	w.compress = compress
+	return w
+}
+
+// SetMaxBackups keeps at most n rotated segments, deleting the oldest
+// ones beyond that. Zero (the default) means no limit by count.
+func (w *FileLogWriter) SetMaxBackups(n int) *FileLogWriter {
+	w.maxBackups = n
+	return w
+}
+
+// SetMaxAgeDays deletes rotated segments older than n days. Zero (the
+// default) means no limit by age.
+func (w *FileLogWriter) SetMaxAgeDays(n int) *FileLogWriter {
+	w.maxAgeDays = n
+	return w
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// gzipFile compresses src into src+".gz" and removes src, returning the
+// compressed file's path.
+func gzipFile(src string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	dst := src + ".gz"
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return "", err
+	}
+
+	gw := gzip.NewWriter(out)
+	if _, err := io.Copy(gw, in); err != nil {
+		gw.Close()
+		out.Close()
+		return "", err
+	}
+	if err := gw.Close(); err != nil {
+		out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+
+	os.Remove(src)
+	return dst, nil
+}
+
+// pruneRotatedSegments deletes rotated segments of base beyond maxBackups
+// (keeping the most recent) and/or older than maxAgeDays. Either limit
+// may be zero to disable that check.
+func pruneRotatedSegments(base string, maxBackups, maxAgeDays int) error {
+	if maxBackups <= 0 && maxAgeDays <= 0 {
+		return nil
+	}
+
+	matches, err := filepath.Glob(base + ".*")
+	if err != nil {
+		return err
+	}
+
+	type segment struct {
+		path    string
+		modTime time.Time
+	}
+
+	segments := make([]segment, 0, len(matches))
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment{path: m, modTime: info.ModTime()})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].modTime.After(segments[j].modTime)
+	})
+
+	cutoff := time.Now().AddDate(0, 0, -maxAgeDays)
+	for i, seg := range segments {
+		tooOld := maxAgeDays > 0 && seg.modTime.Before(cutoff)
+		tooMany := maxBackups > 0 && i >= maxBackups
+		if tooOld || tooMany {
+			os.Remove(seg.path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+//go:build !windows
+
+package log4go
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes a non-blocking advisory exclusive lock on f, to be held
+// only around a single write, so that multiple processes appending to the
+// same log file don't interleave individual writes. It reports contention
+// via an error instead of blocking, since a permanent lock held for the
+// life of the file descriptor would make a second process's writer hang
+// at startup rather than interleave safely.
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+// unlockFile releases a lock taken by lockFile.
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,18 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+//go:build windows
+
+package log4go
+
+import "os"
+
+// lockFile is a no-op on Windows; flock has no equivalent there and
+// O_APPEND writes are already atomic per-write on NTFS.
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on Windows; see lockFile.
+func unlockFile(f *os.File) error {
+	return nil
+}
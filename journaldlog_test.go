@@ -0,0 +1,75 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestJournaldEntrySimpleFields(t *testing.T) {
+	rec := &LogRecord{
+		Level:   INFO,
+		Created: time.Now(),
+		Source:  "main.go:1",
+		Message: "hello",
+	}
+
+	entry := journaldEntry("myapp", rec)
+
+	for _, want := range []string{"PRIORITY=6\n", "SYSLOG_IDENTIFIER=myapp\n", "CODE_FUNC=main.go:1\n", "MESSAGE=hello\n"} {
+		if !bytes.Contains(entry, []byte(want)) {
+			t.Errorf("journaldEntry() = %q, want it to contain %q", entry, want)
+		}
+	}
+}
+
+func TestJournaldEntryMultilineValueUsesExplicitLengthForm(t *testing.T) {
+	rec := &LogRecord{
+		Level:   ERROR,
+		Created: time.Now(),
+		Message: "line one\nline two",
+	}
+
+	entry := journaldEntry("myapp", rec)
+
+	if bytes.Contains(entry, []byte("MESSAGE=line one")) {
+		t.Errorf("journaldEntry() = %q, multiline value must not use the MESSAGE=... form", entry)
+	}
+
+	var buf bytes.Buffer
+	writeField(&buf, "MESSAGE", rec.Message)
+	want := buf.Bytes()
+	if !bytes.Contains(entry, want) {
+		t.Errorf("journaldEntry() = %q, want it to contain the explicit-length encoding %q", entry, want)
+	}
+}
+
+func TestWriteFieldExplicitLengthForm(t *testing.T) {
+	var buf bytes.Buffer
+	writeField(&buf, "MESSAGE", "ab\ncd")
+
+	got := buf.Bytes()
+	if !bytes.HasPrefix(got, []byte("MESSAGE\n")) {
+		t.Fatalf("writeField() = %q, want it to start with the field name followed by a bare newline", got)
+	}
+
+	rest := got[len("MESSAGE\n"):]
+	if len(rest) < 8 {
+		t.Fatalf("writeField() = %q, want an 8-byte little-endian length after the field name", got)
+	}
+
+	var size uint64
+	for i := 0; i < 8; i++ {
+		size |= uint64(rest[i]) << (8 * uint(i))
+	}
+	if got, want := size, uint64(len("ab\ncd")); got != want {
+		t.Errorf("writeField() encoded length = %d, want %d", got, want)
+	}
+
+	value := rest[8:]
+	if got, want := string(value), "ab\ncd\n"; got != want {
+		t.Errorf("writeField() value = %q, want %q", got, want)
+	}
+}
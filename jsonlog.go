@@ -0,0 +1,75 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// jsonLogRecord is the on-the-wire shape written by JsonFormatLogWriter,
+// one object per line.
+type jsonLogRecord struct {
+	Level   string                 `json:"level"`
+	Time    string                 `json:"time"`
+	Source  string                 `json:"source,omitempty"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// JsonFormatLogWriter writes each LogRecord as a single line of JSON,
+// including any structured Fields attached via With or the *w methods.
+type JsonFormatLogWriter struct {
+	rec      chan *LogRecord
+	done     chan struct{}
+	filename string
+	file     *os.File
+}
+
+// NewJsonFormatLogWriter creates a JsonFormatLogWriter that appends to fname.
+func NewJsonFormatLogWriter(fname string) *JsonFormatLogWriter {
+	fd, err := os.OpenFile(fname, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "NewJsonFormatLogWriter(%q): %s\n", fname, err)
+		return nil
+	}
+
+	w := &JsonFormatLogWriter{
+		rec:      make(chan *LogRecord, LogBufferLength),
+		done:     make(chan struct{}),
+		filename: fname,
+		file:     fd,
+	}
+	go w.run()
+	return w
+}
+
+func (w *JsonFormatLogWriter) run() {
+	defer close(w.done)
+
+	enc := json.NewEncoder(w.file)
+	for rec := range w.rec {
+		enc.Encode(jsonLogRecord{
+			Level:   rec.Level.String(),
+			Time:    rec.Created.Format("2006-01-02T15:04:05.000Z07:00"),
+			Source:  rec.Source,
+			Message: rec.Message,
+			Fields:  rec.Fields,
+		})
+	}
+}
+
+// LogWrite logs a record to the JSON writer.
+func (w *JsonFormatLogWriter) LogWrite(rec *LogRecord) {
+	w.rec <- rec
+}
+
+// Close stops the writer's goroutine, waits for it to drain any records
+// still buffered in the channel, and only then closes the underlying
+// file, so run() never writes to an already-closed fd.
+func (w *JsonFormatLogWriter) Close() {
+	close(w.rec)
+	<-w.done
+	w.file.Close()
+}
@@ -0,0 +1,300 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+// Package log4go provides level-based and highly configurable logging.
+//
+// # Enhanced Logging
+//
+// This is inspired by the logging methods used by log4j.
+//
+// # Description
+//
+// Log4go provides level-based and highly configurable logging.
+// It is backwards compatible with the builtin log package, and so can be
+// used as a drop-in replacement for it, but it adds a number of extra
+// features:
+//
+//   - filtering on a per-writer, per-level basis
+//   - pluggable output writers (console, file, socket, ...)
+//   - configurable formats per writer
+//   - on-disk configuration via XML or JSON
+package log4go
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Version information
+const (
+	L4G_VERSION = "log4go-v3.0.1"
+	L4G_MAJOR   = 3
+	L4G_MINOR   = 0
+	L4G_BUILD   = 1
+)
+
+// Level is the level at which a message is logged.
+type Level int
+
+// Logging levels, from least to most severe.
+const (
+	FINEST Level = iota
+	FINE
+	DEBUG
+	TRACE
+	INFO
+	WARNING
+	ERROR
+	CRITICAL
+)
+
+// Logging level strings
+var (
+	levelStrings = [...]string{"FNST", "FINE", "DEBG", "TRAC", "INFO", "WARN", "EROR", "CRIT"}
+)
+
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelStrings) {
+		return "UNKNOWN"
+	}
+	return levelStrings[int(l)]
+}
+
+// A LogRecord contains all of the pertinent information for each message
+type LogRecord struct {
+	Level   Level                  // The log level
+	Created time.Time              // The time at which the log message was created
+	Source  string                 // The message source
+	Message string                 // The log message
+	Fields  map[string]interface{} // Structured key/value context attached via With or the *w helpers
+}
+
+// LogWriter is the interface that provides the functionality for a log
+// target, e.g. a file or network socket. LogWrite will be called to log
+// a LogRecord message, and Close will be called when the logger is shut
+// down or the filter is removed/replaced.
+type LogWriter interface {
+	// This will be called to log a LogRecord message.
+	LogWrite(rec *LogRecord)
+
+	// This should clean up anything lingering about the LogWriter, as it is
+	// called before the LogWriter is removed. LogWrite should not be called
+	// after Close.
+	Close()
+}
+
+// A Filter represents the log level below which no log records are written
+// to the associated LogWriter.
+type Filter struct {
+	Level
+	LogWriter
+}
+
+// A Logger represents a collection of Filters through which log messages
+// are written.
+type Logger map[string]*Filter
+
+// NewLogger creates a new empty Logger.
+func NewLogger() Logger {
+	os.Stdout.WriteString(L4G_VERSION + "\n")
+	return make(Logger)
+}
+
+// NewDefaultLogger creates a new Logger with a "stdout" filter configured
+// to log every message at or above the given level to the console.
+func NewDefaultLogger(lvl Level) Logger {
+	log := NewLogger()
+	log.AddFilter("stdout", lvl, NewConsoleLogWriter())
+	return log
+}
+
+// Close removes all filters (and closes their log writers) from the logger.
+func (log Logger) Close() {
+	for name, filt := range log {
+		filt.Close()
+		delete(log, name)
+	}
+}
+
+// AddFilter adds the given writer to the logger such that it will only
+// log messages at lvl or higher.
+func (log Logger) AddFilter(name string, lvl Level, writer LogWriter) Logger {
+	log[name] = &Filter{lvl, writer}
+	return log
+}
+
+// intLogf sends a formatted log message internally
+func (log Logger) intLogf(lvl Level, format string, args ...interface{}) {
+	skip := true
+
+	// Determine if any logging will be done
+	for _, filt := range log {
+		if lvl >= filt.Level {
+			skip = false
+			break
+		}
+	}
+	if skip {
+		return
+	}
+
+	// Records built here carry no source; use Log to supply one
+	src := ""
+
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, redactArgs(args)...)
+	}
+
+	// Make the log record
+	rec := &LogRecord{
+		Level:   lvl,
+		Created: time.Now(),
+		Source:  src,
+		Message: msg,
+	}
+
+	// Dispatch the logs
+	for _, filt := range log {
+		if lvl < filt.Level {
+			continue
+		}
+		filt.LogWrite(rec)
+	}
+}
+
+// intLogc sends a log message generated by the given closure if it will
+// be logged at the given level.
+func (log Logger) intLogc(lvl Level, closure func() string) {
+	skip := true
+
+	for _, filt := range log {
+		if lvl >= filt.Level {
+			skip = false
+			break
+		}
+	}
+	if skip {
+		return
+	}
+
+	rec := &LogRecord{
+		Level:   lvl,
+		Created: time.Now(),
+		Source:  "",
+		Message: closure(),
+	}
+
+	for _, filt := range log {
+		if lvl < filt.Level {
+			continue
+		}
+		filt.LogWrite(rec)
+	}
+}
+
+// Log sends a log message manually, specifying the source of the message.
+func (log Logger) Log(lvl Level, source, message string) {
+	skip := true
+
+	for _, filt := range log {
+		if lvl >= filt.Level {
+			skip = false
+			break
+		}
+	}
+	if skip {
+		return
+	}
+
+	rec := &LogRecord{
+		Level:   lvl,
+		Created: time.Now(),
+		Source:  source,
+		Message: message,
+	}
+
+	for _, filt := range log {
+		if lvl < filt.Level {
+			continue
+		}
+		filt.LogWrite(rec)
+	}
+}
+
+// Logf logs a formatted message at the given level.
+func (log Logger) Logf(lvl Level, format string, args ...interface{}) {
+	log.intLogf(lvl, format, args...)
+}
+
+// Logc logs a closure-generated message at the given level.
+func (log Logger) Logc(lvl Level, closure func() string) {
+	log.intLogc(lvl, closure)
+}
+
+func (log Logger) Finest(arg0 interface{}, args ...interface{}) {
+	log.logWithLevel(FINEST, arg0, args...)
+}
+
+func (log Logger) Fine(arg0 interface{}, args ...interface{}) {
+	log.logWithLevel(FINE, arg0, args...)
+}
+
+func (log Logger) Debug(arg0 interface{}, args ...interface{}) {
+	log.logWithLevel(DEBUG, arg0, args...)
+}
+
+func (log Logger) Trace(arg0 interface{}, args ...interface{}) {
+	log.logWithLevel(TRACE, arg0, args...)
+}
+
+func (log Logger) Info(arg0 interface{}, args ...interface{}) {
+	log.logWithLevel(INFO, arg0, args...)
+}
+
+func (log Logger) Warn(arg0 interface{}, args ...interface{}) error {
+	return log.logWithLevelErr(WARNING, arg0, args...)
+}
+
+func (log Logger) Error(arg0 interface{}, args ...interface{}) error {
+	return log.logWithLevelErr(ERROR, arg0, args...)
+}
+
+func (log Logger) Critical(arg0 interface{}, args ...interface{}) error {
+	return log.logWithLevelErr(CRITICAL, arg0, args...)
+}
+
+func (log Logger) logWithLevel(lvl Level, arg0 interface{}, args ...interface{}) {
+	switch first := arg0.(type) {
+	case string:
+		log.intLogf(lvl, first, args...)
+	case func() string:
+		log.intLogc(lvl, first)
+	default:
+		log.intLogf(lvl, fmt.Sprint(arg0)+fmtRepeat(len(args)), args...)
+	}
+}
+
+func (log Logger) logWithLevelErr(lvl Level, arg0 interface{}, args ...interface{}) error {
+	switch first := arg0.(type) {
+	case string:
+		log.intLogf(lvl, first, args...)
+		return fmt.Errorf(first, redactArgs(args)...)
+	case func() string:
+		str := first()
+		log.intLogf(lvl, "%s", str)
+		return fmt.Errorf("%s", str)
+	default:
+		msg := fmt.Sprint(arg0) + fmt.Sprintf(fmtRepeat(len(args)), redactArgs(args)...)
+		log.intLogf(lvl, "%s", msg)
+		return fmt.Errorf("%s", msg)
+	}
+}
+
+func fmtRepeat(n int) string {
+	out := ""
+	for i := 0; i < n; i++ {
+		out += " %v"
+	}
+	return out
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// levelNode is one segment of a dotted (well, slash-delimited import path)
+// module tree. A nil level means "no explicit threshold here; inherit from
+// the nearest ancestor that has one".
+type levelNode struct {
+	mu       sync.RWMutex
+	level    *Level
+	children map[string]*levelNode
+	// sep is the separator ('.' or '/') that joined this node's parent to
+	// it in the path it was first created from, so collectLevels can
+	// reconstruct the original path instead of always using '/'.
+	sep byte
+}
+
+func newLevelNode() *levelNode {
+	return &levelNode{children: make(map[string]*levelNode)}
+}
+
+// packageLevels is the root of the per-package level tree. GetGlobalLevel
+// and SetGlobalLevel operate on its root entry, so a package with no
+// explicit level of its own inherits the global level.
+var packageLevels = newLevelNode()
+
+// packageLevelCache memoizes resolved lookups by package path for the hot
+// logging path; it is invalidated whenever a level is set.
+var packageLevelCache sync.Map // map[string]Level
+
+func init() {
+	lvl := GlobalLevel
+	packageLevels.level = &lvl
+}
+
+// SetPackageLevel sets the minimum level that will be logged for messages
+// originating in pkg (and, unless overridden, any package nested under
+// it). pkg is a dotted or slash-delimited module path, e.g.
+// "github.com/me/app/db".
+func SetPackageLevel(pkg string, lvl Level) {
+	parts, seps := splitPackagePath(pkg)
+	node := packageLevels
+	for i, part := range parts {
+		node.mu.Lock()
+		child, ok := node.children[part]
+		if !ok {
+			child = newLevelNode()
+			if i > 0 {
+				child.sep = seps[i-1]
+			}
+			node.children[part] = child
+		}
+		node.mu.Unlock()
+		node = child
+	}
+
+	node.mu.Lock()
+	l := lvl
+	node.level = &l
+	node.mu.Unlock()
+
+	clearPackageLevelCache()
+}
+
+// GetPackageLevel returns the effective level for pkg: its own explicit
+// level if set, or else the nearest ancestor's, falling back to the
+// global level.
+func GetPackageLevel(pkg string) Level {
+	if lvl, ok := packageLevelCache.Load(pkg); ok {
+		return lvl.(Level)
+	}
+
+	node := packageLevels
+	node.mu.RLock()
+	effective := *node.level
+	node.mu.RUnlock()
+
+	parts, _ := splitPackagePath(pkg)
+	for _, part := range parts {
+		node.mu.RLock()
+		child, ok := node.children[part]
+		node.mu.RUnlock()
+		if !ok {
+			break
+		}
+		node = child
+
+		node.mu.RLock()
+		if node.level != nil {
+			effective = *node.level
+		}
+		node.mu.RUnlock()
+	}
+
+	packageLevelCache.Store(pkg, effective)
+	return effective
+}
+
+// callerLevel returns the effective level for whichever package called the
+// wrapper.go entry point skip frames above this function, falling back to
+// the global level if the caller can't be determined.
+func callerLevel(skip int) Level {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return GetGlobalLevel()
+	}
+	return GetPackageLevel(callerPackage(pc))
+}
+
+// callerPackage extracts the import path from a function's runtime name,
+// e.g. "github.com/me/app/db.(*Store).Get" -> "github.com/me/app/db".
+func callerPackage(pc uintptr) string {
+	full := runtime.FuncForPC(pc).Name()
+
+	slash := strings.LastIndex(full, "/")
+	rest := full[slash+1:]
+	if dot := strings.Index(rest, "."); dot >= 0 {
+		return full[:slash+1] + rest[:dot]
+	}
+	return full
+}
+
+// splitPackagePath splits pkg into its segments, along with the separator
+// ('.' or '/') found between each consecutive pair, so callers that build
+// the tree can remember how the original path was written.
+func splitPackagePath(pkg string) (parts []string, seps []byte) {
+	if pkg == "" {
+		return nil, nil
+	}
+	start := 0
+	for i := 0; i < len(pkg); i++ {
+		if pkg[i] == '.' || pkg[i] == '/' {
+			parts = append(parts, pkg[start:i])
+			seps = append(seps, pkg[i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, pkg[start:])
+	return parts, seps
+}
+
+func clearPackageLevelCache() {
+	packageLevelCache.Range(func(k, _ interface{}) bool {
+		packageLevelCache.Delete(k)
+		return true
+	})
+}
+
+// levelThresholdJSON is the wire format used by LevelHandler.
+type levelThresholdJSON struct {
+	Package string `json:"package"`
+	Level   string `json:"level"`
+}
+
+// LevelHandler returns an http.Handler that lets operators inspect and
+// adjust per-package levels on a running service without a restart.
+//
+//	GET  /        -> [{"package": "", "level": "INFO"}, ...] (the global level plus every explicit override)
+//	PUT  /        -> body {"package": "github.com/me/app/db", "level": "WARNING"}, sets that package's level
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(collectLevels("", packageLevels))
+		case http.MethodPut:
+			var req levelThresholdJSON
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			lvl, ok := levelFromString(req.Level)
+			if !ok {
+				http.Error(w, "unknown level "+req.Level, http.StatusBadRequest)
+				return
+			}
+			if req.Package == "" {
+				SetGlobalLevel(lvl)
+			} else {
+				SetPackageLevel(req.Package, lvl)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func collectLevels(prefix string, node *levelNode) []levelThresholdJSON {
+	var out []levelThresholdJSON
+
+	node.mu.RLock()
+	if node.level != nil {
+		out = append(out, levelThresholdJSON{Package: prefix, Level: node.level.String()})
+	}
+	children := make(map[string]*levelNode, len(node.children))
+	for k, v := range node.children {
+		children[k] = v
+	}
+	node.mu.RUnlock()
+
+	for part, child := range children {
+		childPrefix := part
+		if prefix != "" {
+			sep := child.sep
+			if sep == 0 {
+				sep = '/'
+			}
+			childPrefix = prefix + string(sep) + part
+		}
+		out = append(out, collectLevels(childPrefix, child)...)
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+// This file is an external (log4go_test) test package on purpose: the
+// per-package level only matters once a caller outside log4go itself is the
+// one doing the logging, so the regression it guards against (Infow
+// resolving the log4go package's own level instead of the actual caller's)
+// can only be reproduced from across the package boundary.
+package log4go_test
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"log4go"
+)
+
+type recordingLogWriter struct {
+	mu   sync.Mutex
+	recs []*log4go.LogRecord
+}
+
+func (w *recordingLogWriter) LogWrite(rec *log4go.LogRecord) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.recs = append(w.recs, rec)
+}
+
+func (w *recordingLogWriter) Close() {}
+
+func (w *recordingLogWriter) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.recs)
+}
+
+// currentPackage mirrors log4go's own (unexported) callerPackage helper, so
+// the test can target a SetPackageLevel override at exactly the package
+// Infow's internal caller resolution is supposed to land on: this one.
+func currentPackage() string {
+	pc, _, _, _ := runtime.Caller(1)
+	full := runtime.FuncForPC(pc).Name()
+
+	slash := strings.LastIndex(full, "/")
+	rest := full[slash+1:]
+	if dot := strings.Index(rest, "."); dot >= 0 {
+		return full[:slash+1] + rest[:dot]
+	}
+	return full
+}
+
+func TestGlobalInfowRespectsCallingPackageLevel(t *testing.T) {
+	prevGlobal := log4go.Global
+	defer func() { log4go.Global = prevGlobal }()
+
+	spy := &recordingLogWriter{}
+	testLogger := make(log4go.Logger)
+	testLogger.AddFilter("spy", log4go.FINEST, spy)
+	log4go.Global = testLogger
+
+	pkg := currentPackage()
+	defer func(prev log4go.Level) { log4go.SetPackageLevel(pkg, prev) }(log4go.GetPackageLevel(pkg))
+
+	log4go.SetPackageLevel(pkg, log4go.ERROR)
+	log4go.Infow("suppressed")
+	if got := spy.count(); got != 0 {
+		t.Errorf("Infow() wrote %d records with this package's level set to ERROR, want 0", got)
+	}
+
+	log4go.SetPackageLevel(pkg, log4go.FINEST)
+	log4go.Infow("allowed")
+	if got := spy.count(); got != 1 {
+		t.Errorf("Infow() wrote %d records with this package's level set to FINEST, want 1", got)
+	}
+}
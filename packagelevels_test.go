@@ -0,0 +1,66 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import "testing"
+
+func TestGetPackageLevelInheritsFromAncestor(t *testing.T) {
+	defer func(prev Level) { SetGlobalLevel(prev) }(GetGlobalLevel())
+	SetGlobalLevel(DEBUG)
+
+	SetPackageLevel("example.com/app", WARNING)
+
+	if got, want := GetPackageLevel("example.com/app"), WARNING; got != want {
+		t.Errorf("GetPackageLevel(%q) = %v, want %v", "example.com/app", got, want)
+	}
+	if got, want := GetPackageLevel("example.com/app/db"), WARNING; got != want {
+		t.Errorf("GetPackageLevel(%q) = %v, want %v (inherited from ancestor)", "example.com/app/db", got, want)
+	}
+}
+
+func TestGetPackageLevelMoreSpecificOverridesAncestor(t *testing.T) {
+	defer func(prev Level) { SetGlobalLevel(prev) }(GetGlobalLevel())
+	SetGlobalLevel(DEBUG)
+
+	SetPackageLevel("example.com/app", WARNING)
+	SetPackageLevel("example.com/app/db", ERROR)
+
+	if got, want := GetPackageLevel("example.com/app/db"), ERROR; got != want {
+		t.Errorf("GetPackageLevel(%q) = %v, want %v (its own override)", "example.com/app/db", got, want)
+	}
+	if got, want := GetPackageLevel("example.com/app/db/sql"), ERROR; got != want {
+		t.Errorf("GetPackageLevel(%q) = %v, want %v (inherited from the nearer ancestor)", "example.com/app/db/sql", got, want)
+	}
+	if got, want := GetPackageLevel("example.com/app/http"), WARNING; got != want {
+		t.Errorf("GetPackageLevel(%q) = %v, want %v (sibling of db, not affected by its override)", "example.com/app/http", got, want)
+	}
+}
+
+func TestGetPackageLevelFallsBackToGlobal(t *testing.T) {
+	defer func(prev Level) { SetGlobalLevel(prev) }(GetGlobalLevel())
+	SetGlobalLevel(CRITICAL)
+
+	if got, want := GetPackageLevel("example.com/untouched"), CRITICAL; got != want {
+		t.Errorf("GetPackageLevel(%q) = %v, want %v (no override, falls back to global)", "example.com/untouched", got, want)
+	}
+}
+
+func TestCollectLevelsPreservesOriginalSeparators(t *testing.T) {
+	defer func(prev Level) { SetGlobalLevel(prev) }(GetGlobalLevel())
+	SetGlobalLevel(DEBUG)
+
+	const pkg = "github.com/me/app/db"
+	SetPackageLevel(pkg, ERROR)
+	defer SetPackageLevel(pkg, DEBUG)
+
+	found := false
+	for _, th := range collectLevels("", packageLevels) {
+		if th.Package == pkg {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("collectLevels did not report %q with its original dotted/slash form intact", pkg)
+	}
+}
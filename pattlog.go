@@ -0,0 +1,89 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	FORMAT_DEFAULT = "[%D %T] [%L] (%S) %M"
+	FORMAT_SHORT   = "[%t %d] [%L] %M"
+	FORMAT_ABBREV  = "[%L] %M"
+
+	consoleLogFormat = FORMAT_DEFAULT + "\n"
+)
+
+// LogBufferLength specifies how many log messages a particular log4go
+// LogWriter can buffer at a time before writing them.
+const LogBufferLength = 32
+
+// FormatLogRecord formats a log record using a pattern string, replacing
+// the following tokens: %T (time), %t (short time), %D (date), %d (short
+// date), %L (level), %S (source), %M (message).
+func FormatLogRecord(format string, rec *LogRecord) string {
+	if rec == nil {
+		return "<nil>"
+	}
+	if len(format) == 0 {
+		return ""
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, 64))
+	secs := rec.Created.UnixNano() / 1e9
+
+	pieces := strings.SplitAfter(format, "%")
+	for i, piece := range pieces {
+		if i == 0 {
+			out.WriteString(piece)
+			continue
+		}
+		if len(piece) > 0 {
+			switch piece[0] {
+			case 'T':
+				fmt.Fprint(out, rec.Created.Format("15:04:05 MST"))
+			case 't':
+				fmt.Fprint(out, rec.Created.Format("15:04"))
+			case 'D':
+				fmt.Fprint(out, rec.Created.Format("2006/01/02"))
+			case 'd':
+				fmt.Fprint(out, rec.Created.Format("01/02/06"))
+			case 'L':
+				fmt.Fprint(out, rec.Level.String())
+			case 'S':
+				fmt.Fprint(out, rec.Source)
+			case 'M':
+				fmt.Fprint(out, rec.Message)
+				fmt.Fprint(out, formatFields(rec.Fields))
+			}
+			if len(piece) > 1 {
+				out.WriteString(piece[1:])
+			}
+		}
+		_ = secs
+	}
+	return out.String()
+}
+
+// formatFields renders a LogRecord's Fields as " key=value" pairs, sorted
+// by key so that output is stable across runs.
+func formatFields(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&out, " %s=%v", k, fields[k])
+	}
+	return out.String()
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import "strings"
+
+// Redactor is implemented by values that know how to mask their own
+// sensitive contents before they reach a log line. intLogf and the
+// structured field helpers (With, Infow, ...) call Redacted() on any
+// argument or field value that implements it before formatting.
+type Redactor interface {
+	Redacted() interface{}
+}
+
+// Redact returns s with every character replaced by "*", preserving its
+// length. It's a convenience for wrapping an individual password or token
+// string inline, e.g. log4go.Infof("login: %s", log4go.Redact(password)).
+func Redact(s string) string {
+	return strings.Repeat("*", len(s))
+}
+
+// redactArgs replaces any element of args that implements Redactor with
+// the result of its Redacted() method, without mutating the caller's
+// slice.
+func redactArgs(args []interface{}) []interface{} {
+	var copied []interface{}
+	for i, a := range args {
+		r, ok := a.(Redactor)
+		if !ok {
+			continue
+		}
+		if copied == nil {
+			copied = make([]interface{}, len(args))
+			copy(copied, args)
+		}
+		copied[i] = r.Redacted()
+	}
+	if copied != nil {
+		return copied
+	}
+	return args
+}
+
+// redactValue returns v.Redacted() if v implements Redactor, else v
+// unchanged.
+func redactValue(v interface{}) interface{} {
+	if r, ok := v.(Redactor); ok {
+		return r.Redacted()
+	}
+	return v
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import "testing"
+
+type secretValue string
+
+func (s secretValue) Redacted() interface{} {
+	return "[REDACTED]"
+}
+
+func TestErrorfRedactsReturnedError(t *testing.T) {
+	err := Errorf("login failed for %s", secretValue("hunter2"))
+
+	if got, want := err.Error(), "login failed for [REDACTED]"; got != want {
+		t.Errorf("Errorf(...).Error() = %q, want %q", got, want)
+	}
+}
+
+func TestWarnfRedactsReturnedError(t *testing.T) {
+	err := Warnf("token %s expiring soon", secretValue("abcd1234"))
+
+	if got, want := err.Error(), "token [REDACTED] expiring soon"; got != want {
+		t.Errorf("Warnf(...).Error() = %q, want %q", got, want)
+	}
+}
+
+func TestCriticalfRedactsReturnedError(t *testing.T) {
+	err := Criticalf("fatal for %s", secretValue("hunter2"))
+
+	if got, want := err.Error(), "fatal for [REDACTED]"; got != want {
+		t.Errorf("Criticalf(...).Error() = %q, want %q", got, want)
+	}
+}
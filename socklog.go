@@ -0,0 +1,68 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+)
+
+// This log writer sends output to a socket
+type SocketLogWriter struct {
+	rec      chan *LogRecord
+	sock     net.Conn
+	proto    string
+	hostport string
+}
+
+// NewSocketLogWriter creates a new LogWriter that sends json-encoded log
+// records over the given protocol ("tcp" or "udp") to the given address.
+func NewSocketLogWriter(proto, hostport string) *SocketLogWriter {
+	sock, err := net.Dial(proto, hostport)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "NewSocketLogWriter(%q): %s\n", hostport, err)
+		return nil
+	}
+
+	w := &SocketLogWriter{
+		rec:      make(chan *LogRecord, LogBufferLength),
+		sock:     sock,
+		proto:    proto,
+		hostport: hostport,
+	}
+
+	go w.run()
+	return w
+}
+
+func (w *SocketLogWriter) run() {
+	for rec := range w.rec {
+		js, err := json.Marshal(rec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "SocketLogWriter(%q): %s\n", w.hostport, err)
+			continue
+		}
+
+		_, err = w.sock.Write(js)
+		if err != nil && w.proto == "udp" {
+			// UDP is connectionless; try to reconnect once and retry.
+			if sock, derr := net.Dial(w.proto, w.hostport); derr == nil {
+				w.sock = sock
+				w.sock.Write(js)
+			}
+		}
+	}
+	w.sock.Close()
+}
+
+// LogWrite logs a record to the socket writer.
+func (w *SocketLogWriter) LogWrite(rec *LogRecord) {
+	w.rec <- rec
+}
+
+// Close stops the socket writer's goroutine and closes the connection.
+func (w *SocketLogWriter) Close() {
+	close(w.rec)
+}
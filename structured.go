@@ -0,0 +1,146 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import "time"
+
+// Field is a single piece of structured context attached to a log message
+// through With. The *w methods take alternating key/value pairs instead;
+// a Field passed to them is skipped, since it is not a string key.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Context is a child logger returned by With that carries a fixed set of
+// Fields into every message it logs, in addition to whatever a given call
+// supplies.
+type Context struct {
+	log    Logger
+	fields []Field
+}
+
+// With returns a Context that will attach fields to every message logged
+// through it, alongside the target Logger's own filters.
+func (log Logger) With(fields ...Field) *Context {
+	return &Context{log: log, fields: fields}
+}
+
+// With returns a new Context that extends c with additional fields.
+func (c *Context) With(fields ...Field) *Context {
+	merged := make([]Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &Context{log: c.log, fields: merged}
+}
+
+func (log Logger) intLogw(lvl Level, msg string, fields map[string]interface{}) {
+	skip := true
+	for _, filt := range log {
+		if lvl >= filt.Level {
+			skip = false
+			break
+		}
+	}
+	if skip {
+		return
+	}
+
+	rec := &LogRecord{
+		Level:   lvl,
+		Created: time.Now(),
+		Source:  "",
+		Message: msg,
+		Fields:  fields,
+	}
+
+	for _, filt := range log {
+		if lvl < filt.Level {
+			continue
+		}
+		filt.LogWrite(rec)
+	}
+}
+
+// fieldsToMap merges a slice of Fields with a flat keysAndValues... list
+// (as accepted by the *w methods) into a single map, redacting any value
+// that implements Redactor.
+func fieldsToMap(base []Field, keysAndValues ...interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(base)+len(keysAndValues)/2)
+	for _, f := range base {
+		m[f.Key] = redactValue(f.Value)
+	}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			continue
+		}
+		m[key] = redactValue(keysAndValues[i+1])
+	}
+	return m
+}
+
+// Infow logs msg at INFO along with alternating key/value pairs.
+func (log Logger) Infow(msg string, keysAndValues ...interface{}) {
+	log.intLogw(INFO, msg, fieldsToMap(nil, keysAndValues...))
+}
+
+// Debugw logs msg at DEBUG along with alternating key/value pairs.
+func (log Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	log.intLogw(DEBUG, msg, fieldsToMap(nil, keysAndValues...))
+}
+
+// Warnw logs msg at WARNING along with alternating key/value pairs.
+func (log Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	log.intLogw(WARNING, msg, fieldsToMap(nil, keysAndValues...))
+}
+
+// Errorw logs msg at ERROR along with alternating key/value pairs.
+func (log Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	log.intLogw(ERROR, msg, fieldsToMap(nil, keysAndValues...))
+}
+
+// Criticalw logs msg at CRITICAL along with alternating key/value pairs.
+func (log Logger) Criticalw(msg string, keysAndValues ...interface{}) {
+	log.intLogw(CRITICAL, msg, fieldsToMap(nil, keysAndValues...))
+}
+
+// Infow logs msg at INFO along with c's fields and alternating key/value pairs.
+func (c *Context) Infow(msg string, keysAndValues ...interface{}) {
+	if INFO < callerLevel(1) {
+		return
+	}
+	c.log.intLogw(INFO, msg, fieldsToMap(c.fields, keysAndValues...))
+}
+
+// Debugw logs msg at DEBUG along with c's fields and alternating key/value pairs.
+func (c *Context) Debugw(msg string, keysAndValues ...interface{}) {
+	if DEBUG < callerLevel(1) {
+		return
+	}
+	c.log.intLogw(DEBUG, msg, fieldsToMap(c.fields, keysAndValues...))
+}
+
+// Warnw logs msg at WARNING along with c's fields and alternating key/value pairs.
+func (c *Context) Warnw(msg string, keysAndValues ...interface{}) {
+	if WARNING < callerLevel(1) {
+		return
+	}
+	c.log.intLogw(WARNING, msg, fieldsToMap(c.fields, keysAndValues...))
+}
+
+// Errorw logs msg at ERROR along with c's fields and alternating key/value pairs.
+func (c *Context) Errorw(msg string, keysAndValues ...interface{}) {
+	if ERROR < callerLevel(1) {
+		return
+	}
+	c.log.intLogw(ERROR, msg, fieldsToMap(c.fields, keysAndValues...))
+}
+
+// Criticalw logs msg at CRITICAL along with c's fields and alternating key/value pairs.
+func (c *Context) Criticalw(msg string, keysAndValues ...interface{}) {
+	if CRITICAL < callerLevel(1) {
+		return
+	}
+	c.log.intLogw(CRITICAL, msg, fieldsToMap(c.fields, keysAndValues...))
+}
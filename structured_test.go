@@ -0,0 +1,58 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import "testing"
+
+type redactedString string
+
+func (r redactedString) Redacted() interface{} {
+	return "***"
+}
+
+func TestFieldsToMap(t *testing.T) {
+	m := fieldsToMap([]Field{{Key: "a", Value: 1}}, "b", 2, "c", redactedString("secret"))
+
+	if got, want := m["a"], 1; got != want {
+		t.Errorf("m[%q] = %v, want %v", "a", got, want)
+	}
+	if got, want := m["b"], 2; got != want {
+		t.Errorf("m[%q] = %v, want %v", "b", got, want)
+	}
+	if got, want := m["c"], "***"; got != want {
+		t.Errorf("m[%q] = %v, want %v", "c", got, want)
+	}
+}
+
+func TestFieldsToMapOddKeysAndValuesIgnoresTrailingKey(t *testing.T) {
+	m := fieldsToMap(nil, "a", 1, "dangling")
+
+	if len(m) != 1 {
+		t.Fatalf("fieldsToMap() = %v, want exactly one entry", m)
+	}
+	if got, want := m["a"], 1; got != want {
+		t.Errorf("m[%q] = %v, want %v", "a", got, want)
+	}
+}
+
+func TestFieldsToMapNonStringKeySkipped(t *testing.T) {
+	m := fieldsToMap(nil, 1, "value", "a", "b")
+
+	if _, ok := m["1"]; ok {
+		t.Errorf("fieldsToMap() should skip a non-string key, got %v", m)
+	}
+	if got, want := m["a"], "b"; got != want {
+		t.Errorf("m[%q] = %v, want %v", "a", got, want)
+	}
+}
+
+func TestFormatFields(t *testing.T) {
+	if got, want := formatFields(nil), ""; got != want {
+		t.Errorf("formatFields(nil) = %q, want %q", got, want)
+	}
+
+	got := formatFields(map[string]interface{}{"b": 2, "a": 1})
+	if want := " a=1 b=2"; got != want {
+		t.Errorf("formatFields(...) = %q, want %q (keys must be sorted)", got, want)
+	}
+}
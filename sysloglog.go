@@ -0,0 +1,113 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"fmt"
+	"log/syslog"
+	"os"
+)
+
+// SyslogLogWriter sends log records to a local or remote syslog daemon,
+// mapping log4go Levels onto RFC 5424 severities.
+type SyslogLogWriter struct {
+	rec      chan *LogRecord
+	done     chan struct{}
+	w        *syslog.Writer
+	network  string
+	addr     string
+	facility syslog.Priority
+	tag      string
+}
+
+// syslogFacilities maps the facility names accepted by config files onto
+// the syslog.Priority facility bits.
+var syslogFacilities = map[string]syslog.Priority{
+	"kern": syslog.LOG_KERN, "user": syslog.LOG_USER, "mail": syslog.LOG_MAIL,
+	"daemon": syslog.LOG_DAEMON, "auth": syslog.LOG_AUTH, "syslog": syslog.LOG_SYSLOG,
+	"lpr": syslog.LOG_LPR, "news": syslog.LOG_NEWS, "uucp": syslog.LOG_UUCP,
+	"cron": syslog.LOG_CRON, "authpriv": syslog.LOG_AUTHPRIV, "ftp": syslog.LOG_FTP,
+	"local0": syslog.LOG_LOCAL0, "local1": syslog.LOG_LOCAL1, "local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3, "local4": syslog.LOG_LOCAL4, "local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6, "local7": syslog.LOG_LOCAL7,
+}
+
+// NewSyslogLogWriter creates a SyslogLogWriter. network and addr follow the
+// conventions of syslog.Dial: pass network == "" to log to the local syslog
+// daemon over its well-known Unix socket, or "tcp"/"udp" with a remote addr
+// to log over the network. facility is a syslog facility name such as
+// "local0" or "daemon", defaulting to "user" if unrecognized.
+func NewSyslogLogWriter(network, addr, facility, tag string) *SyslogLogWriter {
+	fac, ok := syslogFacilities[facility]
+	if !ok {
+		fac = syslog.LOG_USER
+	}
+
+	w, err := syslog.Dial(network, addr, fac|syslog.LOG_INFO, tag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "NewSyslogLogWriter(%q, %q): %s\n", network, addr, err)
+		return nil
+	}
+
+	sw := &SyslogLogWriter{
+		rec:      make(chan *LogRecord, LogBufferLength),
+		done:     make(chan struct{}),
+		w:        w,
+		network:  network,
+		addr:     addr,
+		facility: fac,
+		tag:      tag,
+	}
+	go sw.run()
+	return sw
+}
+
+func (w *SyslogLogWriter) run() {
+	defer close(w.done)
+
+	for rec := range w.rec {
+		msg := rec.Message + formatFields(rec.Fields)
+
+		send := func(sw *syslog.Writer) error {
+			switch rec.Level {
+			case FINEST, FINE, DEBUG:
+				return sw.Debug(msg)
+			case TRACE, INFO:
+				return sw.Info(msg)
+			case WARNING:
+				return sw.Warning(msg)
+			case ERROR:
+				return sw.Err(msg)
+			case CRITICAL:
+				return sw.Crit(msg)
+			default:
+				return sw.Info(msg)
+			}
+		}
+
+		if err := send(w.w); err != nil {
+			// The local syslog daemon restarted or the remote
+			// connection dropped; reconnect and retry once, the
+			// same way SocketLogWriter does for UDP.
+			if nw, derr := syslog.Dial(w.network, w.addr, w.facility|syslog.LOG_INFO, w.tag); derr == nil {
+				w.w.Close()
+				w.w = nw
+				send(w.w)
+			}
+		}
+	}
+}
+
+// LogWrite logs a record to the syslog writer.
+func (w *SyslogLogWriter) LogWrite(rec *LogRecord) {
+	w.rec <- rec
+}
+
+// Close stops the writer's goroutine, waits for it to drain any records
+// still buffered in the channel, and only then closes the syslog
+// connection, so run() never writes to an already-closed connection.
+func (w *SyslogLogWriter) Close() {
+	close(w.rec)
+	<-w.done
+	w.w.Close()
+}
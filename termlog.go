@@ -0,0 +1,39 @@
+// Copyright (C) 2010, Kyle Lemons <kyle@kylelemons.net>.  All rights reserved.
+
+package log4go
+
+import (
+	"fmt"
+	"os"
+)
+
+// This is the standard writer that prints to standard output.
+type ConsoleLogWriter struct {
+	w chan *LogRecord
+}
+
+// This creates a new ConsoleLogWriter
+func NewConsoleLogWriter() *ConsoleLogWriter {
+	records := make(chan *LogRecord, LogBufferLength)
+	w := &ConsoleLogWriter{records}
+	go w.run(os.Stdout)
+	return w
+}
+
+func (w *ConsoleLogWriter) run(out *os.File) {
+	for rec := range w.w {
+		fmt.Fprint(out, FormatLogRecord(consoleLogFormat, rec))
+	}
+}
+
+// This is the ConsoleLogWriter's output method. This will block if the output
+// buffer is full.
+func (w *ConsoleLogWriter) LogWrite(rec *LogRecord) {
+	w.w <- rec
+}
+
+// Close stops the logger from sending messages to standard output. Attempts to
+// write to the LogWriter after a Close have undefined behavior.
+func (w *ConsoleLogWriter) Close() {
+	close(w.w)
+}
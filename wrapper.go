@@ -14,6 +14,9 @@ var (
 	Global Logger
 )
 
+// GlobalLevel is kept for compatibility with callers that read it directly;
+// GetGlobalLevel/SetGlobalLevel are the supported way to read or change it,
+// since they also keep the per-package level tree's root in sync.
 var GlobalLevel Level = DEBUG
 
 func init() {
@@ -76,12 +79,70 @@ func Stderr(args ...interface{}) {
 	}
 }
 
+// With returns a Context on the global logger that carries fields into
+// every message logged through it.
+// Wrapper for (Logger).With
+func With(fields ...Field) *Context {
+	return Global.With(fields...)
+}
+
+// Infow logs a message at INFO along with alternating key/value pairs.
+// Wrapper for (Logger).Infow
+func Infow(msg string, keysAndValues ...interface{}) {
+	if INFO < callerLevel(1) {
+		return
+	}
+	Global.Infow(msg, keysAndValues...)
+}
+
+// Debugw logs a message at DEBUG along with alternating key/value pairs.
+// Wrapper for (Logger).Debugw
+func Debugw(msg string, keysAndValues ...interface{}) {
+	if DEBUG < callerLevel(1) {
+		return
+	}
+	Global.Debugw(msg, keysAndValues...)
+}
+
+// Warnw logs a message at WARNING along with alternating key/value pairs.
+// Wrapper for (Logger).Warnw
+func Warnw(msg string, keysAndValues ...interface{}) {
+	if WARNING < callerLevel(1) {
+		return
+	}
+	Global.Warnw(msg, keysAndValues...)
+}
+
+// Errorw logs a message at ERROR along with alternating key/value pairs.
+// Wrapper for (Logger).Errorw
+func Errorw(msg string, keysAndValues ...interface{}) {
+	if ERROR < callerLevel(1) {
+		return
+	}
+	Global.Errorw(msg, keysAndValues...)
+}
+
+// Criticalw logs a message at CRITICAL along with alternating key/value pairs.
+// Wrapper for (Logger).Criticalw
+func Criticalw(msg string, keysAndValues ...interface{}) {
+	if CRITICAL < callerLevel(1) {
+		return
+	}
+	Global.Criticalw(msg, keysAndValues...)
+}
+
+// GetGlobalLevel returns the level at the root of the per-package level
+// tree, i.e. the threshold used by any package with no explicit override
+// of its own.
 func GetGlobalLevel() Level {
-	return GlobalLevel
+	return GetPackageLevel("")
 }
 
+// SetGlobalLevel sets the root of the per-package level tree. Packages
+// with no explicit SetPackageLevel of their own will use this threshold.
 func SetGlobalLevel(level Level) {
 	GlobalLevel = level
+	SetPackageLevel("", level)
 }
 
 // Compatibility with `log`
@@ -104,18 +165,27 @@ func Stdoutf(format string, args ...interface{}) {
 // Send a log message manually
 // Wrapper for (*Logger).Log
 func Log(lvl Level, source, message string) {
+	if lvl < callerLevel(1) {
+		return
+	}
 	Global.Log(lvl, source, message)
 }
 
 // Send a formatted log message easily
 // Wrapper for (*Logger).Logf
 func Logf(lvl Level, format string, args ...interface{}) {
+	if lvl < callerLevel(1) {
+		return
+	}
 	Global.intLogf(lvl, format, args...)
 }
 
 // Send a closure log message
 // Wrapper for (*Logger).Logc
 func Logc(lvl Level, closure func() string) {
+	if lvl < callerLevel(1) {
+		return
+	}
 	Global.intLogc(lvl, closure)
 }
 
@@ -128,7 +198,7 @@ func Debugf(arg0 interface{}, args ...interface{}) {
 	const (
 		lvl = DEBUG
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	switch first := arg0.(type) {
@@ -148,7 +218,7 @@ func Debug(args ...interface{}) {
 	const (
 		lvl = DEBUG
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	pc, _, lineno, ok := runtime.Caller(1)
@@ -166,7 +236,7 @@ func Tracef(arg0 interface{}, args ...interface{}) {
 	const (
 		lvl = TRACE
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	switch first := arg0.(type) {
@@ -186,7 +256,7 @@ func Trace(args ...interface{}) {
 	const (
 		lvl = TRACE
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	pc, _, lineno, ok := runtime.Caller(1)
@@ -204,7 +274,7 @@ func Infof(arg0 interface{}, args ...interface{}) {
 	const (
 		lvl = INFO
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	switch first := arg0.(type) {
@@ -224,7 +294,7 @@ func Info(args ...interface{}) {
 	const (
 		lvl = INFO
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	pc, _, lineno, ok := runtime.Caller(1)
@@ -243,14 +313,14 @@ func Warnf(arg0 interface{}, args ...interface{}) error {
 	const (
 		lvl = WARNING
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return nil
 	}
 	switch first := arg0.(type) {
 	case string:
 		// Use the string as a format string
 		Global.intLogf(lvl, first, args...)
-		return errors.New(fmt.Sprintf(first, args...))
+		return errors.New(fmt.Sprintf(first, redactArgs(args)...))
 	case func() string:
 		// Log the closure (no other arguments used)
 		str := first()
@@ -259,7 +329,7 @@ func Warnf(arg0 interface{}, args ...interface{}) error {
 	default:
 		// Build a format string so that it will be similar to Sprint
 		Global.intLogf(lvl, fmt.Sprint(first)+strings.Repeat(" %v", len(args)), args...)
-		return errors.New(fmt.Sprint(first) + fmt.Sprintf(strings.Repeat(" %v", len(args)), args...))
+		return errors.New(fmt.Sprint(first) + fmt.Sprintf(strings.Repeat(" %v", len(args)), redactArgs(args)...))
 	}
 	return nil
 }
@@ -268,7 +338,7 @@ func Warn(args ...interface{}) {
 	const (
 		lvl = WARNING
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	pc, _, lineno, ok := runtime.Caller(1)
@@ -287,14 +357,14 @@ func Errorf(arg0 interface{}, args ...interface{}) error {
 	const (
 		lvl = ERROR
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return nil
 	}
 	switch first := arg0.(type) {
 	case string:
 		// Use the string as a format string
 		Global.intLogf(lvl, first, args...)
-		return errors.New(fmt.Sprintf(first, args...))
+		return errors.New(fmt.Sprintf(first, redactArgs(args)...))
 	case func() string:
 		// Log the closure (no other arguments used)
 		str := first()
@@ -303,7 +373,7 @@ func Errorf(arg0 interface{}, args ...interface{}) error {
 	default:
 		// Build a format string so that it will be similar to Sprint
 		Global.intLogf(lvl, fmt.Sprint(first)+strings.Repeat(" %v", len(args)), args...)
-		return errors.New(fmt.Sprint(first) + fmt.Sprintf(strings.Repeat(" %v", len(args)), args...))
+		return errors.New(fmt.Sprint(first) + fmt.Sprintf(strings.Repeat(" %v", len(args)), redactArgs(args)...))
 	}
 	return nil
 }
@@ -312,7 +382,7 @@ func Error(args ...interface{}) {
 	const (
 		lvl = ERROR
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	pc, _, lineno, ok := runtime.Caller(1)
@@ -331,14 +401,14 @@ func Criticalf(arg0 interface{}, args ...interface{}) error {
 	const (
 		lvl = CRITICAL
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return nil
 	}
 	switch first := arg0.(type) {
 	case string:
 		// Use the string as a format string
 		Global.intLogf(lvl, first, args...)
-		return errors.New(fmt.Sprintf(first, args...))
+		return errors.New(fmt.Sprintf(first, redactArgs(args)...))
 	case func() string:
 		// Log the closure (no other arguments used)
 		str := first()
@@ -347,7 +417,7 @@ func Criticalf(arg0 interface{}, args ...interface{}) error {
 	default:
 		// Build a format string so that it will be similar to Sprint
 		Global.intLogf(lvl, fmt.Sprint(first)+strings.Repeat(" %v", len(args)), args...)
-		return errors.New(fmt.Sprint(first) + fmt.Sprintf(strings.Repeat(" %v", len(args)), args...))
+		return errors.New(fmt.Sprint(first) + fmt.Sprintf(strings.Repeat(" %v", len(args)), redactArgs(args)...))
 	}
 	return nil
 }
@@ -356,7 +426,7 @@ func Critical(args ...interface{}) {
 	const (
 		lvl = CRITICAL
 	)
-	if lvl < GlobalLevel {
+	if lvl < callerLevel(1) {
 		return
 	}
 	pc, _, lineno, ok := runtime.Caller(1)